@@ -0,0 +1,140 @@
+package regex
+
+// thread is a single active state of the NFA simulation: a program counter plus the
+// capture slots it has accumulated so far. saves[0]/saves[1] bound the whole match
+// (capture group 0); saves[2*i]/saves[2*i+1] bound capture group i, or stay -1 if
+// the group never participated (see prog.go's compile/compileCapture).
+type thread struct {
+	pc    int
+	saves []int
+}
+
+// threadList is one of the two active-state lists (clist/nlist) used while
+// stepping through the input. gen/curGen guard against adding the same pc twice
+// within the same list, which is what keeps the simulation linear in len(input):
+// each pc is visited at most once per input position.
+type threadList struct {
+	threads []thread
+	gen     []int
+	curGen  int
+}
+
+func newThreadList(numInst int) *threadList {
+	return &threadList{gen: make([]int, numInst), curGen: 1}
+}
+
+// reset clears the list for reuse at the next input position.
+func (l *threadList) reset() {
+	l.threads = l.threads[:0]
+	l.curGen++
+}
+
+// newSaves returns a capture-slot slice of the given size with every slot set to
+// -1, meaning "group did not participate" until an opSave instruction says otherwise.
+func newSaves(numSlots int) []int {
+	saves := make([]int, numSlots)
+	for i := range saves {
+		saves[i] = -1
+	}
+	return saves
+}
+
+// addThread adds the state reached by following t.pc through any epsilon
+// transitions (opJmp, opSplit, opSave and the opBegin/opEnd assertions), and
+// appends it to l.threads once a real (rune-consuming or accepting) state is
+// reached. pos is the absolute input position the assertions are evaluated
+// against; input is always the full text being searched, never a subslice, so
+// that '^' and '$' keep meaning "start/end of input" regardless of where the
+// current search attempt began.
+func addThread(p []inst, l *threadList, t thread, input []rune, pos int) {
+	if l.gen[t.pc] == l.curGen {
+		return
+	}
+	l.gen[t.pc] = l.curGen
+
+	switch p[t.pc].op {
+	case opJmp:
+		addThread(p, l, thread{pc: p[t.pc].pc1, saves: t.saves}, input, pos)
+	case opSplit:
+		// Both branches share t.saves until one of them hits opSave, which always
+		// copies before writing, so the alternatives never clobber each other.
+		addThread(p, l, thread{pc: p[t.pc].pc1, saves: t.saves}, input, pos)
+		addThread(p, l, thread{pc: p[t.pc].pc2, saves: t.saves}, input, pos)
+	case opSave:
+		saves := make([]int, len(t.saves))
+		copy(saves, t.saves)
+		saves[p[t.pc].slot] = pos
+		addThread(p, l, thread{pc: t.pc + 1, saves: saves}, input, pos)
+	case opBegin:
+		if pos == 0 {
+			addThread(p, l, thread{pc: t.pc + 1, saves: t.saves}, input, pos)
+		}
+	case opEnd:
+		if pos == len(input) {
+			addThread(p, l, thread{pc: t.pc + 1, saves: t.saves}, input, pos)
+		}
+	default:
+		l.threads = append(l.threads, t)
+	}
+}
+
+// execFind reports whether p matches anywhere in input at or after start, and if
+// so the capture slots of the leftmost-first match (slots 0 and 1 bound the whole
+// match; further pairs bound each capture group, see thread).
+//
+// It runs a single left-to-right scan: as long as no match has been found yet, a
+// fresh thread starting at pc 0 is injected at the current position, which is what
+// makes an unanchored search fall out of the same simulation that handles anchored
+// matches. clist.threads is kept in priority order (earlier entries were added
+// first and therefore started searching earlier, or are the greedier alternative at
+// the same start), so the first thread to reach opMatch during a step wins and any
+// threads after it in that step are abandoned since they can never produce a
+// better match. Because addThread visits each pc at most once per position, the
+// whole search is linear in len(input).
+func execFind(p []inst, input []rune, start, numSlots int) (bool, []int) {
+	clist := newThreadList(len(p))
+	nlist := newThreadList(len(p))
+
+	matched := false
+	var saves []int
+
+	for pos := start; ; pos++ {
+		if !matched {
+			addThread(p, clist, thread{pc: 0, saves: newSaves(numSlots)}, input, pos)
+		}
+
+		hasRune := pos < len(input)
+		var r rune
+		if hasRune {
+			r = input[pos]
+		}
+
+	threadLoop:
+		for _, t := range clist.threads {
+			switch p[t.pc].op {
+			case opChar:
+				if hasRune && p[t.pc].r == r {
+					addThread(p, nlist, thread{pc: t.pc + 1, saves: t.saves}, input, pos+1)
+				}
+			case opAnyChar:
+				if hasRune {
+					addThread(p, nlist, thread{pc: t.pc + 1, saves: t.saves}, input, pos+1)
+				}
+			case opClass:
+				if hasRune && p[t.pc].class.Matches(r) {
+					addThread(p, nlist, thread{pc: t.pc + 1, saves: t.saves}, input, pos+1)
+				}
+			case opMatch:
+				matched = true
+				saves = t.saves
+				break threadLoop
+			}
+		}
+
+		if !hasRune {
+			return matched, saves
+		}
+		clist, nlist = nlist, clist
+		nlist.reset()
+	}
+}
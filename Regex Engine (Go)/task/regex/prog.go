@@ -0,0 +1,211 @@
+package regex
+
+import "regexengine/regex/syntax"
+
+// opcode identifies the kind of a single inst, following the Thompson/Pike
+// virtual-machine design used by RE2 and Go's own regexp package.
+type opcode int
+
+const (
+	opChar    opcode = iota // match a specific rune, advance to pc+1
+	opAnyChar               // match any rune, advance to pc+1
+	opClass                 // match a rune against class, advance to pc+1
+	opBegin                 // zero-width: only passable at position 0
+	opEnd                   // zero-width: only passable at the end of input
+	opMatch                 // accepting state
+	opJmp                   // zero-width: jump to pc1
+	opSplit                 // zero-width: fork to both pc1 and pc2
+	opSave                  // zero-width: record the current position into slot, advance to pc+1
+)
+
+// inst is a single instruction of a compiled program.
+type inst struct {
+	op    opcode
+	r     rune            // operand of opChar
+	class syntax.CharClass // operand of opClass
+	pc1   int             // opJmp/opSplit target, opSplit's first target
+	pc2   int             // opSplit's second target
+	slot  int             // opSave capture slot index
+}
+
+// compile translates an AST node into a flat program, wrapping it with the
+// Save 0 / Save 1 instructions that bound the whole match (capture group 0),
+// followed by a trailing Match.
+func compile(node syntax.Node) []inst {
+	body := shift(compileNode(node), 1)
+	prog := make([]inst, 0, len(body)+3)
+	prog = append(prog, inst{op: opSave, slot: 0})
+	prog = append(prog, body...)
+	prog = append(prog, inst{op: opSave, slot: 1})
+	prog = append(prog, inst{op: opMatch})
+	return prog
+}
+
+// numCaptures returns the number of capture groups in node, i.e. the highest
+// Capture.Index appearing anywhere in the AST (0 if there are none).
+func numCaptures(node syntax.Node) int {
+	max := 0
+	switch n := node.(type) {
+	case syntax.Capture:
+		max = n.Index
+		if sub := numCaptures(n.Sub); sub > max {
+			max = sub
+		}
+	case syntax.Concat:
+		for _, sub := range n.Subs {
+			if c := numCaptures(sub); c > max {
+				max = c
+			}
+		}
+	case syntax.Alt:
+		for _, sub := range n.Subs {
+			if c := numCaptures(sub); c > max {
+				max = c
+			}
+		}
+	case syntax.Star:
+		max = numCaptures(n.Sub)
+	case syntax.Plus:
+		max = numCaptures(n.Sub)
+	case syntax.Question:
+		max = numCaptures(n.Sub)
+	}
+	return max
+}
+
+// compileNode compiles a single AST node into a self-contained fragment whose
+// internal pc1/pc2 targets are relative to the start of the fragment (pc 0).
+func compileNode(node syntax.Node) []inst {
+	switch n := node.(type) {
+	case syntax.Literal:
+		return []inst{{op: opChar, r: n.Rune}}
+	case syntax.AnyChar:
+		return []inst{{op: opAnyChar}}
+	case syntax.CharClass:
+		return []inst{{op: opClass, class: n}}
+	case syntax.Begin:
+		return []inst{{op: opBegin}}
+	case syntax.End:
+		return []inst{{op: opEnd}}
+	case syntax.Capture:
+		return compileCapture(n.Index, compileNode(n.Sub))
+	case syntax.Concat:
+		var frag []inst
+		for _, sub := range n.Subs {
+			frag = append(frag, shift(compileNode(sub), len(frag))...)
+		}
+		return frag
+	case syntax.Alt:
+		return compileAlt(n.Subs)
+	case syntax.Star:
+		return compileStar(compileNode(n.Sub))
+	case syntax.Plus:
+		return compilePlus(compileNode(n.Sub))
+	case syntax.Question:
+		return compileQuestion(compileNode(n.Sub))
+	default:
+		return nil
+	}
+}
+
+// shift returns a copy of frag with every pc1/pc2 target offset by offset, so that
+// it can be appended after other instructions without invalidating its internal jumps.
+func shift(frag []inst, offset int) []inst {
+	shifted := make([]inst, len(frag))
+	for i, in := range frag {
+		in.pc1 += offset
+		in.pc2 += offset
+		shifted[i] = in
+	}
+	return shifted
+}
+
+// compileCapture compiles "(sub)" as:
+//
+//	Save 2*index
+//	<codes for sub>
+//	Save 2*index+1
+//
+// mirroring the Save 0 / Save 1 pair compile() wraps the whole expression in for
+// the implicit group 0.
+func compileCapture(index int, sub []inst) []inst {
+	body := shift(sub, 1)
+	frag := make([]inst, 0, len(body)+2)
+	frag = append(frag, inst{op: opSave, slot: 2 * index})
+	frag = append(frag, body...)
+	frag = append(frag, inst{op: opSave, slot: 2*index + 1})
+	return frag
+}
+
+// compileAlt compiles "e1 | e2 | ... | en" as a right-leaning chain of splits:
+//
+//	split L1, L2
+//	L1: e1
+//	jmp Lend
+//	L2: e2 | e3 | ... | en
+//	Lend:
+func compileAlt(subs []syntax.Node) []inst {
+	if len(subs) == 1 {
+		return compileNode(subs[0])
+	}
+
+	left := shift(compileNode(subs[0]), 1)
+	jmpPC := 1 + len(left)
+	right := shift(compileAlt(subs[1:]), jmpPC+1)
+	end := jmpPC + 1 + len(right)
+
+	frag := make([]inst, 0, end)
+	frag = append(frag, inst{op: opSplit, pc1: 1, pc2: jmpPC + 1})
+	frag = append(frag, left...)
+	frag = append(frag, inst{op: opJmp, pc1: end})
+	frag = append(frag, right...)
+	return frag
+}
+
+// compileStar compiles "e*" as:
+//
+//	L1: split L2, L3
+//	L2: e
+//	    jmp L1
+//	L3:
+func compileStar(sub []inst) []inst {
+	body := shift(sub, 1)
+	jmpPC := 1 + len(body)
+	end := jmpPC + 1
+
+	frag := make([]inst, 0, end)
+	frag = append(frag, inst{op: opSplit, pc1: 1, pc2: end})
+	frag = append(frag, body...)
+	frag = append(frag, inst{op: opJmp, pc1: 0})
+	return frag
+}
+
+// compilePlus compiles "e+" as:
+//
+//	L1: e
+//	L2: split L1, L3
+//	L3:
+func compilePlus(sub []inst) []inst {
+	splitPC := len(sub)
+	end := splitPC + 1
+
+	frag := make([]inst, 0, end)
+	frag = append(frag, sub...)
+	frag = append(frag, inst{op: opSplit, pc1: 0, pc2: end})
+	return frag
+}
+
+// compileQuestion compiles "e?" as:
+//
+//	split L1, L2
+//	L1: e
+//	L2:
+func compileQuestion(sub []inst) []inst {
+	body := shift(sub, 1)
+	end := 1 + len(body)
+
+	frag := make([]inst, 0, end)
+	frag = append(frag, inst{op: opSplit, pc1: 1, pc2: end})
+	frag = append(frag, body...)
+	return frag
+}
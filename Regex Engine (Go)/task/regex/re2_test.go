@@ -0,0 +1,110 @@
+package regex
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// TestRE2Search checks this package against a hand-authored corpus of (regex,
+// string, expected match span) cases, modeled on Go's own TestRE2Search: a text
+// file of stanzas, each a "strings" block of Go-quoted inputs followed by a
+// "regexps" block in which every regex is immediately followed by one result
+// line per string in the preceding block, in order: "-" for no match, or the
+// leftmost match span "start-end" (rune indices) otherwise. This lets future
+// rewrites of the engine be checked against a fixed oracle.
+func TestRE2Search(t *testing.T) {
+	const file = "testdata/re2-search.txt"
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var (
+		strs      []string // strings declared by the current "strings" block
+		pending   []string // strings still awaiting a result line for the current regexp
+		re        *Regexp
+		inStrings bool
+	)
+
+	scanner := bufio.NewScanner(f)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := scanner.Text()
+		switch {
+		case line == "" || line[0] == '#':
+			continue
+		case line == "strings":
+			strs = strs[:0]
+			inStrings = true
+		case line == "regexps":
+			inStrings = false
+		case line[0] == '"':
+			q, err := strconv.Unquote(line)
+			if err != nil {
+				t.Fatalf("%s:%d: unquote %s: %v", file, lineno, line, err)
+			}
+			if inStrings {
+				strs = append(strs, q)
+				continue
+			}
+			if len(pending) != 0 {
+				t.Fatalf("%s:%d: out of sync: %d results still pending for %q", file, lineno, len(pending), re)
+			}
+			re, err = Compile(q)
+			if err != nil {
+				t.Errorf("%s:%d: Compile(%q): %v", file, lineno, q, err)
+				continue
+			}
+			pending = append([]string(nil), strs...)
+		case line == "-" || ('0' <= line[0] && line[0] <= '9'):
+			if re == nil {
+				continue // the regexp on this line's stanza failed to compile
+			}
+			if len(pending) == 0 {
+				t.Fatalf("%s:%d: out of sync: no strings left for %q", file, lineno, re)
+			}
+			var text string
+			text, pending = pending[0], pending[1:]
+
+			want, err := parseSpan(line)
+			if err != nil {
+				t.Fatalf("%s:%d: %v", file, lineno, err)
+			}
+			got := re.FindStringIndex(text)
+			if !sameSpan(got, want) {
+				t.Errorf("%s:%d: Compile(%q).FindStringIndex(%q) = %v, want %v", file, lineno, re, text, got, want)
+			}
+		default:
+			t.Fatalf("%s:%d: out of sync: %s", file, lineno, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("%s: %v", file, err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("%s: out of sync: %d strings left unused for %q", file, len(pending), re)
+	}
+}
+
+// parseSpan parses a result line: "-" for no match, or "start-end" for a match.
+func parseSpan(line string) ([]int, error) {
+	if line == "-" {
+		return nil, nil
+	}
+	var start, end int
+	if _, err := fmt.Sscanf(line, "%d-%d", &start, &end); err != nil {
+		return nil, fmt.Errorf("malformed result %q: %v", line, err)
+	}
+	return []int{start, end}, nil
+}
+
+// sameSpan reports whether got and want represent the same match result.
+func sameSpan(got, want []int) bool {
+	if got == nil || want == nil {
+		return len(got) == len(want)
+	}
+	return got[0] == want[0] && got[1] == want[1]
+}
@@ -0,0 +1,17 @@
+// Package regex implements the matching engine behind the CLI in package main.
+//
+// Matching is a Thompson-style NFA simulation (see exec.go), compiled (see
+// prog.go) from an AST produced by the regex/syntax package. This guarantees
+// matching is linear in the length of the input, unlike the backtracking
+// implementation it replaces.
+package regex
+
+// Match reports whether the regular expression expr matches somewhere in s.
+// It returns false, rather than an error, for a malformed expr.
+func Match(expr, s string) bool {
+	re, err := Compile(expr)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(s)
+}
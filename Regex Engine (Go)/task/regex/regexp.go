@@ -0,0 +1,163 @@
+package regex
+
+import (
+	"fmt"
+	"strings"
+
+	"regexengine/regex/syntax"
+)
+
+// Regexp is a compiled regular expression, modeled on the standard library's
+// regexp.Regexp but backed by this package's Thompson NFA.
+type Regexp struct {
+	expr     string
+	prog     []inst
+	numSlots int // 2 * (number of capture groups + 1), sized for group 0 plus each (...) group
+}
+
+// Compile parses expr and returns a compiled Regexp, or an error if expr is malformed.
+func Compile(expr string) (*Regexp, error) {
+	node, err := syntax.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &Regexp{expr: expr, prog: compile(node), numSlots: 2 * (numCaptures(node) + 1)}, nil
+}
+
+// MustCompile is like Compile but panics if expr cannot be parsed.
+func MustCompile(expr string) *Regexp {
+	re, err := Compile(expr)
+	if err != nil {
+		panic(fmt.Sprintf("regex: Compile(%q): %v", expr, err))
+	}
+	return re
+}
+
+// String returns the source text used to compile re.
+func (re *Regexp) String() string {
+	return re.expr
+}
+
+// MatchString reports whether s contains any match of re.
+func (re *Regexp) MatchString(s string) bool {
+	matched, _ := execFind(re.prog, []rune(s), 0, re.numSlots)
+	return matched
+}
+
+// FindStringIndex returns a two-element slice of rune indices giving the start and
+// end of the leftmost match in s, or nil if there is no match.
+func (re *Regexp) FindStringIndex(s string) []int {
+	matched, saves := execFind(re.prog, []rune(s), 0, re.numSlots)
+	if !matched {
+		return nil
+	}
+	return []int{saves[0], saves[1]}
+}
+
+// FindStringSubmatchIndex returns index pairs identifying the leftmost match of re
+// in s and the spans of its submatches, or nil if there is no match. The first
+// pair is the whole match; the ith pair after that is the span of capture group
+// i, or {-1, -1} if that group did not participate in the match.
+func (re *Regexp) FindStringSubmatchIndex(s string) []int {
+	matched, saves := execFind(re.prog, []rune(s), 0, re.numSlots)
+	if !matched {
+		return nil
+	}
+	return saves
+}
+
+// FindStringSubmatch is like FindStringSubmatchIndex but returns the matched text
+// instead of indices; a group that did not participate in the match is reported as
+// the empty string, the same way the standard library's regexp package does.
+func (re *Regexp) FindStringSubmatch(s string) []string {
+	locs := re.FindStringSubmatchIndex(s)
+	if locs == nil {
+		return nil
+	}
+	runes := []rune(s)
+	matches := make([]string, len(locs)/2)
+	for i := range matches {
+		start, end := locs[2*i], locs[2*i+1]
+		if start < 0 || end < 0 {
+			continue
+		}
+		matches[i] = string(runes[start:end])
+	}
+	return matches
+}
+
+// FindString returns the leftmost match of re in s, or the empty string if there is
+// no match. As with the standard library, this can't distinguish a no-match empty
+// string from a matched empty string; use FindStringIndex for that.
+func (re *Regexp) FindString(s string) string {
+	loc := re.FindStringIndex(s)
+	if loc == nil {
+		return ""
+	}
+	return string([]rune(s)[loc[0]:loc[1]])
+}
+
+// FindAllStringIndex returns the start/end rune indices of successive
+// non-overlapping matches of re in s, at most n of them (n < 0 means unlimited), or
+// nil if there is no match. As in the standard library, an empty match is not
+// returned again at the same position as the previous match: the search resumes
+// one rune further on so it keeps making progress.
+func (re *Regexp) FindAllStringIndex(s string, n int) [][]int {
+	runes := []rune(s)
+	var all [][]int
+	prevMatchEnd := -1
+	for pos := 0; pos <= len(runes) && (n < 0 || len(all) < n); {
+		matched, saves := execFind(re.prog, runes, pos, re.numSlots)
+		if !matched {
+			break
+		}
+		if saves[0] == saves[1] && saves[0] == prevMatchEnd {
+			pos++
+			continue
+		}
+		all = append(all, []int{saves[0], saves[1]})
+		prevMatchEnd = saves[1]
+		if saves[1] > pos {
+			pos = saves[1]
+		} else {
+			pos++
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	return all
+}
+
+// FindAllString returns the successive non-overlapping matches of re in s, at most
+// n of them (n < 0 means unlimited), or nil if there is no match.
+func (re *Regexp) FindAllString(s string, n int) []string {
+	locs := re.FindAllStringIndex(s, n)
+	if locs == nil {
+		return nil
+	}
+	runes := []rune(s)
+	matches := make([]string, len(locs))
+	for i, loc := range locs {
+		matches[i] = string(runes[loc[0]:loc[1]])
+	}
+	return matches
+}
+
+// ReplaceAllString returns a copy of src with all matches of re replaced by repl.
+func (re *Regexp) ReplaceAllString(src, repl string) string {
+	locs := re.FindAllStringIndex(src, -1)
+	if locs == nil {
+		return src
+	}
+	runes := []rune(src)
+	var b strings.Builder
+	prev := 0
+	for _, loc := range locs {
+		b.WriteString(string(runes[prev:loc[0]]))
+		b.WriteString(repl)
+		prev = loc[1]
+	}
+	b.WriteString(string(runes[prev:]))
+	return b.String()
+}
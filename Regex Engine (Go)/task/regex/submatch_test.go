@@ -0,0 +1,40 @@
+package regex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindStringSubmatch(t *testing.T) {
+	cases := []struct {
+		expr, input string
+		want        []string
+	}{
+		{`(a+)(b)?c`, "xxaaacxx", []string{"aaac", "aaa", ""}},
+		{`(a+)(b)?c`, "xxaaabcxx", []string{"aaabc", "aaa", "b"}},
+		{`(a+)(b)?c`, "xxxx", nil},
+		{`(a(b)c)`, "abc", []string{"abc", "abc", "b"}},
+		{`(a|b)+`, "ababab", []string{"ababab", "b"}},
+	}
+	for _, c := range cases {
+		re := MustCompile(c.expr)
+		got := re.FindStringSubmatch(c.input)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("MustCompile(%q).FindStringSubmatch(%q) = %#v, want %#v", c.expr, c.input, got, c.want)
+		}
+	}
+}
+
+func TestFindStringSubmatchIndex(t *testing.T) {
+	re := MustCompile(`(a+)(b)?c`)
+
+	if got, want := re.FindStringSubmatchIndex("xxaaacxx"), []int{2, 6, 2, 5, -1, -1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindStringSubmatchIndex(%q) = %v, want %v", "xxaaacxx", got, want)
+	}
+	if got, want := re.FindStringSubmatchIndex("xxaaabcxx"), []int{2, 7, 2, 5, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindStringSubmatchIndex(%q) = %v, want %v", "xxaaabcxx", got, want)
+	}
+	if got := re.FindStringSubmatchIndex("xxxx"); got != nil {
+		t.Errorf("FindStringSubmatchIndex(%q) = %v, want nil", "xxxx", got)
+	}
+}
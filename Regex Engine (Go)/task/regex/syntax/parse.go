@@ -0,0 +1,171 @@
+package syntax
+
+import "fmt"
+
+// parser holds the state used while parsing a regex expression into an AST.
+type parser struct {
+	runes  []rune
+	pos    int
+	ngroup int // number of '(' seen so far, used to assign Capture.Index
+}
+
+// Parse parses expr into an AST following the grammar documented on the package.
+func Parse(expr string) (Node, error) {
+	p := &parser{runes: []rune(expr)}
+	node, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.runes) {
+		return nil, fmt.Errorf("syntax: unexpected %q at position %d", p.runes[p.pos], p.pos)
+	}
+	return node, nil
+}
+
+// parseAlt parses "concatenation { '|' concatenation }".
+func (p *parser) parseAlt() (Node, error) {
+	first, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	subs := []Node{first}
+	for p.pos < len(p.runes) && p.runes[p.pos] == '|' {
+		p.pos++
+		next, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, next)
+	}
+	if len(subs) == 1 {
+		return subs[0], nil
+	}
+	return Alt{Subs: subs}, nil
+}
+
+// parseConcat parses "{ closure }", stopping at '|', ')' or the end of expr.
+func (p *parser) parseConcat() (Node, error) {
+	var subs []Node
+	for p.pos < len(p.runes) && p.runes[p.pos] != '|' && p.runes[p.pos] != ')' {
+		term, err := p.parseClosure()
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, term)
+	}
+	switch len(subs) {
+	case 0:
+		return Concat{}, nil
+	case 1:
+		return subs[0], nil
+	default:
+		return Concat{Subs: subs}, nil
+	}
+}
+
+// parseClosure parses "term [ '*' | '+' | '?' ]".
+func (p *parser) parseClosure() (Node, error) {
+	term, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.runes) {
+		return term, nil
+	}
+	switch p.runes[p.pos] {
+	case '*':
+		p.pos++
+		return Star{Sub: term}, nil
+	case '+':
+		p.pos++
+		return Plus{Sub: term}, nil
+	case '?':
+		p.pos++
+		return Question{Sub: term}, nil
+	default:
+		return term, nil
+	}
+}
+
+// parseTerm parses a single term: an anchor, the wildcard, an escape, a char
+// class, a capture group or a literal char.
+func (p *parser) parseTerm() (Node, error) {
+	if p.pos >= len(p.runes) {
+		return nil, fmt.Errorf("syntax: unexpected end of expression")
+	}
+	r := p.runes[p.pos]
+	switch r {
+	case '^':
+		p.pos++
+		return Begin{}, nil
+	case '$':
+		p.pos++
+		return End{}, nil
+	case '.':
+		p.pos++
+		return AnyChar{}, nil
+	case '[':
+		return p.parseCharClass()
+	case '(':
+		return p.parseCapture()
+	case '\\':
+		p.pos++
+		if p.pos >= len(p.runes) {
+			return nil, fmt.Errorf("syntax: trailing '\\'")
+		}
+		escaped := p.runes[p.pos]
+		p.pos++
+		return Literal{Rune: escaped}, nil
+	default:
+		p.pos++
+		return Literal{Rune: r}, nil
+	}
+}
+
+// parseCapture parses "'(' regexp ')'". The leading '(' must still be at p.pos
+// when this is called. Groups are numbered left-to-right by their opening '(',
+// starting at 1 (group 0 is the implicit whole match).
+func (p *parser) parseCapture() (Node, error) {
+	p.pos++ // consumes '('
+	p.ngroup++
+	index := p.ngroup
+
+	sub, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.runes) || p.runes[p.pos] != ')' {
+		return nil, fmt.Errorf("syntax: missing closing ')'")
+	}
+	p.pos++
+	return Capture{Index: index, Sub: sub}, nil
+}
+
+// parseCharClass parses "'[' ['^'] { character-range } ']'". The leading '[' must
+// still be at p.pos when this is called.
+func (p *parser) parseCharClass() (Node, error) {
+	p.pos++ // consumes '['
+	var class CharClass
+	if p.pos < len(p.runes) && p.runes[p.pos] == '^' {
+		class.Negate = true
+		p.pos++
+	}
+	for {
+		if p.pos >= len(p.runes) {
+			return nil, fmt.Errorf("syntax: unterminated '['")
+		}
+		if p.runes[p.pos] == ']' {
+			p.pos++
+			return class, nil
+		}
+		lo := p.runes[p.pos]
+		p.pos++
+		if p.pos+1 < len(p.runes) && p.runes[p.pos] == '-' && p.runes[p.pos+1] != ']' {
+			hi := p.runes[p.pos+1]
+			p.pos += 2
+			class.Ranges = append(class.Ranges, [2]rune{lo, hi})
+		} else {
+			class.Ranges = append(class.Ranges, [2]rune{lo, lo})
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Package syntax parses a regular expression into an AST.
+//
+// The grammar is the classic one used by Go's own old/regexp package, extended
+// with parenthesized subexpressions for capture groups:
+//
+//	regexp:        concatenation { '|' concatenation }
+//	concatenation: { closure }
+//	closure:       term [ '*' | '+' | '?' ]
+//	term:          '^' | '$' | '.' | character
+//	               | '[' ['^'] { character-range } ']'
+//	               | '\\' character
+//	               | '(' regexp ')'
+package syntax
+
+// Node is implemented by every node in the AST produced by Parse.
+type Node interface {
+	node()
+}
+
+// Literal matches a single specific rune.
+type Literal struct {
+	Rune rune
+}
+
+// AnyChar matches any single rune (the '.' wildcard).
+type AnyChar struct{}
+
+// CharClass matches any rune covered by Ranges, e.g. "[abc]" or "[a-z]".
+// A lone char such as 'a' is stored as the range {'a', 'a'}. Negate inverts
+// membership, as in "[^abc]".
+type CharClass struct {
+	Negate bool
+	Ranges [][2]rune
+}
+
+// Matches reports whether r is a member of the class, taking Negate into account.
+func (c CharClass) Matches(r rune) bool {
+	member := false
+	for _, rg := range c.Ranges {
+		if r >= rg[0] && r <= rg[1] {
+			member = true
+			break
+		}
+	}
+	if c.Negate {
+		return !member
+	}
+	return member
+}
+
+// Concat matches each of Subs in turn.
+type Concat struct {
+	Subs []Node
+}
+
+// Alt matches the first of Subs that matches (top-level "a|b|c").
+type Alt struct {
+	Subs []Node
+}
+
+// Star matches Sub zero or more times.
+type Star struct {
+	Sub Node
+}
+
+// Plus matches Sub one or more times.
+type Plus struct {
+	Sub Node
+}
+
+// Question matches Sub zero or one times.
+type Question struct {
+	Sub Node
+}
+
+// Capture matches Sub and records its span as capture group Index (1-based;
+// group 0 is the implicit whole match, which Capture never represents).
+type Capture struct {
+	Index int
+	Sub   Node
+}
+
+// Begin matches the empty string at the start of the input ("^").
+type Begin struct{}
+
+// End matches the empty string at the end of the input ("$").
+type End struct{}
+
+func (Literal) node()   {}
+func (AnyChar) node()   {}
+func (CharClass) node() {}
+func (Capture) node()   {}
+func (Concat) node()    {}
+func (Alt) node()       {}
+func (Star) node()      {}
+func (Plus) node()      {}
+func (Question) node()  {}
+func (Begin) node()     {}
+func (End) node()       {}